@@ -7,16 +7,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
-	"math/rand"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"reflect"
 	"regexp"
 	"time"
-
-	"github.com/PathDNA/ptk"
 )
 
 // URIFormat defines the endpoint for a single app
@@ -37,7 +33,34 @@ type API struct {
 	User  string
 	Debug bool
 
+	// Backoff controls the pause between retries. If nil, a fresh
+	// ExponentialBackoff is used for each call; a custom instance set
+	// here is reused across calls, so it must tolerate that (or be
+	// restricted to a single goroutine).
+	Backoff BackoffStrategy
+
+	// Logger receives gochimp3's debug/error tracing. If nil, it falls
+	// back to the standard "log" package, matching prior behavior.
+	Logger Logger
+
+	// MaxConcurrent caps how many requests this API instance will have
+	// in flight at once, honoring Mailchimp's per-account concurrent
+	// connection limit. Zero falls back to DefaultMaxConcurrent. It can
+	// be changed at any time, even after New, and takes effect on the
+	// next request.
+	MaxConcurrent int
+
+	// Limiter, if set, is waited on before every request, for pacing
+	// (e.g. a token-bucket golang.org/x/time/rate.Limiter) in addition
+	// to the MaxConcurrent cap.
+	Limiter Limiter
+
 	endpoint string
+	authMode authMode
+	sem      *concurrencyLimiter
+
+	beforeRequest []RequestMiddleware
+	afterResponse []ResponseMiddleware
 }
 
 // New creates a API
@@ -47,30 +70,82 @@ func New(apiKey string) *API {
 	u.Host = fmt.Sprintf(URIFormat, DatacenterRegex.FindString(apiKey))
 	u.Path = Version
 
-	return &API{
-		User:     "gochimp3",
-		Key:      apiKey,
-		endpoint: u.String(),
+	api := &API{
+		User:          "gochimp3",
+		Key:           apiKey,
+		endpoint:      u.String(),
+		MaxConcurrent: DefaultMaxConcurrent,
 	}
+	api.sem = newConcurrencyLimiter(maxConcurrent(api.MaxConcurrent))
+	return api
 }
 
 func (api API) Request(method, path string, params QueryParams, body, response interface{}) error {
-	// Retry 5 times every 2 minutes
-	return ptk.RetryCtx(context.Background(), func() error {
-		if err := api.rawRequest(method, path, params, body, response); err != nil {
+	return api.RequestWithContext(context.Background(), method, path, params, body, response)
+}
+
+// RequestWithContext behaves like Request but carries ctx through the
+// retry loop and the underlying HTTP request, so callers can cancel a
+// bulk operation or enforce a per-call deadline.
+func (api API) RequestWithContext(ctx context.Context, method, path string, params QueryParams, body, response interface{}) error {
+	backoff := api.Backoff
+	if backoff == nil {
+		backoff = NewExponentialBackoff()
+	}
+	backoff.Reset()
+
+	for {
+		err := api.rawRequest(ctx, method, path, params, body, response)
+		if err == nil {
+			return nil
+		}
+
+		if !isRetryable(err) {
 			return err
 		}
 
-		return nil
-	}, 5, time.Duration(Random(1, 10)*time.Minute), 0)
+		pause, again := backoff.Pause()
+		if !again {
+			return err
+		}
+
+		if apiErr, ok := err.(*APIError); ok && apiErr.RetryAfter > 0 {
+			pause = apiErr.RetryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+	}
 }
 
-func Random(min, max int) time.Duration {
-	return time.Duration(rand.Intn(max-min) + min)
+// isRetryable reports whether err is a network error, an HTTP 5xx, or an
+// HTTP 429 - the only cases worth retrying. 4xx client errors and
+// responses whose body cannot be decoded as JSON (whatever their status
+// code) are returned to the caller immediately. Any other error that is
+// not an *APIError, including one returned by a middleware or by the
+// Limiter, is treated as a network error and retried.
+func isRetryable(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		// A response arrived but its body could not be decoded.
+		return false
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		// No structured API error and no decode error: treated as a
+		// transport-level/network error.
+		return true
+	}
+
+	return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
 }
 
 // Request will make a call to the actual API.
-func (api API) rawRequest(method, path string, params QueryParams, body, response interface{}) error {
+func (api API) rawRequest(ctx context.Context, method, path string, params QueryParams, body, response interface{}) error {
 	client := &http.Client{Transport: api.Transport}
 	if api.Timeout > 0 {
 		client.Timeout = api.Timeout
@@ -78,7 +153,7 @@ func (api API) rawRequest(method, path string, params QueryParams, body, respons
 
 	requestURL := fmt.Sprintf("%s%s", api.endpoint, path)
 	if api.Debug {
-		log.Printf("Requesting %s: %s\n", method, requestURL)
+		api.logger().Debugf("Requesting %s: %s\n", method, requestURL)
 	}
 
 	var bodyBytes io.Reader
@@ -91,17 +166,21 @@ func (api API) rawRequest(method, path string, params QueryParams, body, respons
 		}
 		bodyBytes = bytes.NewBuffer(data)
 		if api.Debug {
-			log.Printf("Adding body: %+v\n", body)
+			api.logger().Debugf("Adding body: %+v\n", body)
 		}
 	}
 
-	req, err := http.NewRequest(method, requestURL, bodyBytes)
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyBytes)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(api.User, api.Key)
+	if api.authMode == authModeOAuth {
+		req.Header.Set("Authorization", "Bearer "+api.Key)
+	} else {
+		req.SetBasicAuth(api.User, api.Key)
+	}
 
 	if params != nil && !reflect.ValueOf(params).IsNil() {
 		queryParams := req.URL.Query()
@@ -113,24 +192,52 @@ func (api API) rawRequest(method, path string, params QueryParams, body, respons
 		req.URL.RawQuery = queryParams.Encode()
 
 		if api.Debug {
-			log.Printf("Adding query params: %q\n", req.URL.Query())
+			api.logger().Debugf("Adding query params: %q\n", req.URL.Query())
 		}
 	}
 
 	if api.Debug {
 		dump, _ := httputil.DumpRequestOut(req, true)
-		log.Printf("%s", string(dump))
+		api.logger().Debugf("%s", string(dump))
 	}
 
+	for _, mw := range api.beforeRequest {
+		if err := mw(req); err != nil {
+			return err
+		}
+	}
+
+	if api.Limiter != nil {
+		if err := api.Limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	sem := api.sem
+	if sem == nil {
+		sem = newConcurrencyLimiter(maxConcurrent(api.MaxConcurrent))
+	}
+	release, err := sem.acquire(ctx, maxConcurrent(api.MaxConcurrent))
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	for _, mw := range api.afterResponse {
+		if err := mw(resp); err != nil {
+			return err
+		}
+	}
+
 	if api.Debug {
 		dump, _ := httputil.DumpResponse(resp, true)
-		log.Printf("%s", string(dump))
+		api.logger().Debugf("%s", string(dump))
 	}
 
 	data, err = ioutil.ReadAll(resp.Body)
@@ -153,25 +260,20 @@ func (api API) rawRequest(method, path string, params QueryParams, body, respons
 	}
 
 	// This is an API Error
-	return parseAPIError(data)
+	return api.parseAPIError(resp, data)
 }
 
 // RequestOk Make Request ignoring body and return true if HTTP status code is 2xx.
 func (api API) RequestOk(method, path string) (bool, error) {
-	err := api.Request(method, path, nil, nil, nil)
-	if err != nil {
-		return false, err
-	}
-	return true, nil
+	return api.RequestOkWithContext(context.Background(), method, path)
 }
 
-func parseAPIError(data []byte) error {
-	log.Println("MAILCHIMP ERROR", string(data))
-	apiError := new(APIError)
-	err := json.Unmarshal(data, apiError)
+// RequestOkWithContext behaves like RequestOk but carries ctx through to
+// RequestWithContext.
+func (api API) RequestOkWithContext(ctx context.Context, method, path string) (bool, error) {
+	err := api.RequestWithContext(ctx, method, path, nil, nil, nil)
 	if err != nil {
-		return err
+		return false, err
 	}
-
-	return apiError
+	return true, nil
 }
@@ -0,0 +1,100 @@
+package gochimp3
+
+import (
+	"math/rand"
+	"time"
+)
+
+// BackoffStrategy decides how long to wait between retries of a failed
+// request. Pause returns the duration to sleep and whether a further
+// retry is permitted. Reset must be called before starting a new retry
+// sequence, since implementations commonly track elapsed time and/or
+// attempt count across calls to Pause.
+type BackoffStrategy interface {
+	Pause() (time.Duration, bool)
+	Reset()
+}
+
+// NoRetry never retries; the first failure is final.
+type NoRetry struct{}
+
+// Pause always reports that no further retry is permitted.
+func (NoRetry) Pause() (time.Duration, bool) { return 0, false }
+
+// Reset is a no-op; NoRetry carries no state.
+func (NoRetry) Reset() {}
+
+// ExponentialBackoff doubles (by Multiplier) the pause between retries,
+// starting from InitialInterval and capped at MaxInterval, with
+// RandomizationFactor applied as jitter so concurrent callers don't
+// retry in lockstep.
+type ExponentialBackoff struct {
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	Multiplier          float64
+	RandomizationFactor float64
+
+	// MaxElapsedTime bounds the whole retry sequence; once exceeded,
+	// Pause reports no further retry. Zero means no bound.
+	MaxElapsedTime time.Duration
+
+	current   time.Duration
+	startedAt time.Time
+}
+
+// NewExponentialBackoff returns an ExponentialBackoff with sensible
+// defaults: a 500ms initial delay, a 2 minute cap, doubling each time,
+// jittered by up to 50%, bounded to 10 minutes of total elapsed retry
+// time. The bound matters for Request/RequestOk, which retry with
+// context.Background() and would otherwise have no way to stop short of
+// a permanent 5xx/429 on their own.
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		InitialInterval:     500 * time.Millisecond,
+		MaxInterval:         2 * time.Minute,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+		MaxElapsedTime:      10 * time.Minute,
+	}
+}
+
+// Pause returns the next jittered delay and advances the internal
+// interval towards MaxInterval.
+func (b *ExponentialBackoff) Pause() (time.Duration, bool) {
+	if b.startedAt.IsZero() {
+		b.startedAt = time.Now()
+		b.current = b.InitialInterval
+	}
+
+	if b.MaxElapsedTime > 0 && time.Since(b.startedAt) > b.MaxElapsedTime {
+		return 0, false
+	}
+
+	pause := jitter(b.current, b.RandomizationFactor)
+
+	b.current = time.Duration(float64(b.current) * b.Multiplier)
+	if b.current > b.MaxInterval {
+		b.current = b.MaxInterval
+	}
+
+	return pause, true
+}
+
+// Reset clears elapsed-time tracking and the current interval so the
+// next Pause call starts a fresh sequence from InitialInterval.
+func (b *ExponentialBackoff) Reset() {
+	b.startedAt = time.Time{}
+	b.current = 0
+}
+
+func jitter(d time.Duration, factor float64) time.Duration {
+	if factor <= 0 {
+		return d
+	}
+
+	delta := factor * float64(d)
+	min := float64(d) - delta
+	max := float64(d) + delta
+
+	return time.Duration(min + rand.Float64()*(max-min))
+}
@@ -0,0 +1,107 @@
+package gochimp3
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoffJitterBounds(t *testing.T) {
+	b := &ExponentialBackoff{
+		InitialInterval:     time.Second,
+		MaxInterval:         10 * time.Second,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		pause, ok := b.Pause()
+		if !ok {
+			t.Fatalf("expected Pause to allow a retry")
+		}
+		if pause < 0 {
+			t.Fatalf("pause must never be negative, got %v", pause)
+		}
+		if pause > b.MaxInterval+b.MaxInterval/2 {
+			t.Fatalf("pause %v exceeds MaxInterval plus jitter", pause)
+		}
+	}
+}
+
+func TestExponentialBackoffMaxElapsedTime(t *testing.T) {
+	b := &ExponentialBackoff{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+		MaxElapsedTime:  10 * time.Millisecond,
+	}
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if _, ok := b.Pause(); !ok {
+			return
+		}
+	}
+	t.Fatalf("expected Pause to eventually report no further retries once MaxElapsedTime passed")
+}
+
+func TestNewExponentialBackoffHasBoundedElapsedTime(t *testing.T) {
+	b := NewExponentialBackoff()
+	if b.MaxElapsedTime <= 0 {
+		t.Fatalf("default ExponentialBackoff must bound MaxElapsedTime so Request/RequestOk (which use context.Background()) can't retry forever")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", errors.New("dial tcp: timeout"), true},
+		{"5xx", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"4xx", &APIError{StatusCode: http.StatusNotFound}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isRetryable(c.err); got != c.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Run("delta seconds", func(t *testing.T) {
+		d, ok := parseRetryAfter("120")
+		if !ok || d != 120*time.Second {
+			t.Fatalf("got (%v, %v), want (120s, true)", d, ok)
+		}
+	})
+
+	t.Run("http date", func(t *testing.T) {
+		future := time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat)
+		d, ok := parseRetryAfter(future)
+		if !ok {
+			t.Fatalf("expected ok for a valid HTTP-date")
+		}
+		if d <= 0 || d > 3*time.Minute {
+			t.Fatalf("unexpected duration %v for a ~2m future HTTP-date", d)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, ok := parseRetryAfter(""); ok {
+			t.Fatalf("expected ok=false for an empty header")
+		}
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		if _, ok := parseRetryAfter("not-a-valid-value"); ok {
+			t.Fatalf("expected ok=false for an unparseable header")
+		}
+	})
+}
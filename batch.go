@@ -0,0 +1,221 @@
+package gochimp3
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// MaxBatchOperations is the most sub-requests Mailchimp will accept in a
+// single batch.
+const MaxBatchOperations = 500
+
+// Batch queues sub-requests to submit together against Mailchimp's
+// /batches endpoint, instead of issuing one HTTP request per call.
+type Batch struct {
+	api        API
+	operations []batchOperation
+}
+
+type batchOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// NewBatch creates an empty Batch bound to this API.
+func (api API) NewBatch() *Batch {
+	return &Batch{api: api}
+}
+
+// Add queues a sub-request. body, if non-nil, is JSON-encoded into the
+// operation's "body" field, matching what the live endpoint expects.
+func (b *Batch) Add(method, path string, body interface{}) error {
+	if len(b.operations) >= MaxBatchOperations {
+		return fmt.Errorf("gochimp3: batch already has the maximum of %d operations", MaxBatchOperations)
+	}
+
+	op := batchOperation{Method: method, Path: path}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		op.Body = string(data)
+	}
+
+	b.operations = append(b.operations, op)
+	return nil
+}
+
+// BatchOperation reports the status of a batch submitted via
+// Batch.Submit, as returned by POST/GET /batches/{id}.
+type BatchOperation struct {
+	ID                 string    `json:"id"`
+	Status             string    `json:"status"`
+	TotalOperations    int       `json:"total_operations"`
+	FinishedOperations int       `json:"finished_operations"`
+	ErroredOperations  int       `json:"errored_operations"`
+	SubmittedAt        time.Time `json:"submitted_at"`
+	CompletedAt        time.Time `json:"completed_at"`
+	ResponseBodyURL    string    `json:"response_body_url"`
+
+	api API
+}
+
+// Submit posts the queued operations to Mailchimp as a single batch.
+func (b *Batch) Submit() (*BatchOperation, error) {
+	return b.SubmitWithContext(context.Background())
+}
+
+// SubmitWithContext behaves like Submit but carries ctx through the
+// underlying request.
+func (b *Batch) SubmitWithContext(ctx context.Context) (*BatchOperation, error) {
+	body := struct {
+		Operations []batchOperation `json:"operations"`
+	}{Operations: b.operations}
+
+	op := new(BatchOperation)
+	if err := b.api.RequestWithContext(ctx, http.MethodPost, "/batches", nil, body, op); err != nil {
+		return nil, err
+	}
+
+	op.api = b.api
+	return op, nil
+}
+
+// GetBatchOperation fetches the current status of a previously submitted
+// batch.
+func (api API) GetBatchOperation(id string) (*BatchOperation, error) {
+	return api.GetBatchOperationWithContext(context.Background(), id)
+}
+
+// GetBatchOperationWithContext behaves like GetBatchOperation but
+// carries ctx through the underlying request.
+func (api API) GetBatchOperationWithContext(ctx context.Context, id string) (*BatchOperation, error) {
+	op := new(BatchOperation)
+	if err := api.RequestWithContext(ctx, http.MethodGet, fmt.Sprintf("/batches/%s", id), nil, nil, op); err != nil {
+		return nil, err
+	}
+
+	op.api = api
+	return op, nil
+}
+
+// BatchOperationList is a page of batch summaries, per GET /batches.
+type BatchOperationList struct {
+	Operations []BatchOperation `json:"batches"`
+	TotalItems int              `json:"total_items"`
+}
+
+// ListBatchOperations lists previously submitted batches.
+func (api API) ListBatchOperations(params QueryParams) (*BatchOperationList, error) {
+	return api.ListBatchOperationsWithContext(context.Background(), params)
+}
+
+// ListBatchOperationsWithContext behaves like ListBatchOperations but
+// carries ctx through the underlying request.
+func (api API) ListBatchOperationsWithContext(ctx context.Context, params QueryParams) (*BatchOperationList, error) {
+	list := new(BatchOperationList)
+	if err := api.RequestWithContext(ctx, http.MethodGet, "/batches", params, nil, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteBatchOperation stops a batch request from running and removes
+// it.
+func (api API) DeleteBatchOperation(id string) (bool, error) {
+	return api.DeleteBatchOperationWithContext(context.Background(), id)
+}
+
+// DeleteBatchOperationWithContext behaves like DeleteBatchOperation but
+// carries ctx through the underlying request.
+func (api API) DeleteBatchOperationWithContext(ctx context.Context, id string) (bool, error) {
+	return api.RequestOkWithContext(ctx, http.MethodDelete, fmt.Sprintf("/batches/%s", id))
+}
+
+// BatchResult is one sub-request's response, unpacked from the tarball
+// a finished batch's ResponseBodyURL points to.
+type BatchResult struct {
+	StatusCode  int             `json:"status_code"`
+	OperationID string          `json:"operation_id"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// Wait polls the batch's status every pollInterval until Mailchimp marks
+// it "finished", then downloads and unpacks the per-operation responses.
+func (op *BatchOperation) Wait(ctx context.Context, pollInterval time.Duration) ([]BatchResult, error) {
+	for op.Status != "finished" {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		next, err := op.api.GetBatchOperationWithContext(ctx, op.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		*op = *next
+	}
+
+	return op.downloadResults(ctx)
+}
+
+// downloadResults streams the gzipped tarball at ResponseBodyURL and
+// unmarshals each member file's JSON array of per-operation responses.
+func (op *BatchOperation) downloadResults(ctx context.Context) ([]BatchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, op.ResponseBodyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Transport: op.api.Transport}
+	if op.api.Timeout > 0 {
+		client.Timeout = op.api.Timeout
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var results []BatchResult
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		var fileResults []BatchResult
+		if err := json.NewDecoder(tr).Decode(&fileResults); err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,137 @@
+package gochimp3
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tarGzFixture builds a single-member gzipped tar archive containing the
+// JSON array Mailchimp would put in a batch's response_body_url.
+func tarGzFixture(t *testing.T, results []BatchResult) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "results.json",
+		Mode: 0600,
+		Size: int64(len(data)),
+	}); err != nil {
+		t.Fatalf("write tar header: %v", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		t.Fatalf("write tar body: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip writer: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDownloadResultsUnpacksTarGz(t *testing.T) {
+	want := []BatchResult{
+		{StatusCode: 200, OperationID: "op1", Response: json.RawMessage(`{"ok":true}`)},
+		{StatusCode: 404, OperationID: "op2", Response: json.RawMessage(`{"ok":false}`)},
+	}
+	fixture := tarGzFixture(t, want)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	op := &BatchOperation{ResponseBodyURL: srv.URL}
+	got, err := op.downloadResults(context.Background())
+	if err != nil {
+		t.Fatalf("downloadResults: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].StatusCode != want[i].StatusCode ||
+			got[i].OperationID != want[i].OperationID ||
+			string(got[i].Response) != string(want[i].Response) {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWaitPollsUntilFinished(t *testing.T) {
+	var calls int32
+	fixture := tarGzFixture(t, []BatchResult{{StatusCode: 200, OperationID: "op1"}})
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/batches/abc123", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		status := "pending"
+		if n >= 3 {
+			status = "finished"
+		}
+		json.NewEncoder(w).Encode(BatchOperation{
+			ID:              "abc123",
+			Status:          status,
+			ResponseBodyURL: srv.URL + "/batch-results.tar.gz",
+		})
+	})
+	mux.HandleFunc("/batch-results.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fixture)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	op := &BatchOperation{
+		ID:     "abc123",
+		Status: "pending",
+		api:    API{endpoint: srv.URL},
+	}
+
+	results, err := op.Wait(context.Background(), 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n < 3 {
+		t.Errorf("expected Wait to poll at least 3 times before the batch finished, got %d", n)
+	}
+	if len(results) != 1 || results[0].OperationID != "op1" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestWaitRespectsContextCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(BatchOperation{ID: "abc123", Status: "pending"})
+	}))
+	defer srv.Close()
+
+	op := &BatchOperation{ID: "abc123", Status: "pending", api: API{endpoint: srv.URL}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := op.Wait(ctx, 5*time.Millisecond); err != context.DeadlineExceeded {
+		t.Fatalf("Wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,70 @@
+package gochimp3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError represents the problem+json error document Mailchimp returns
+// for non-2xx responses.
+// See https://mailchimp.com/developer/marketing/docs/errors/
+type APIError struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+
+	// StatusCode mirrors Status but is always populated from the HTTP
+	// response itself, so the retry loop can classify the failure
+	// without re-parsing (or trusting) the body.
+	StatusCode int `json:"-"`
+
+	// RetryAfter is populated from the Retry-After header on 429 and
+	// 503 responses, when present.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (a *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", a.Title, a.Detail, a.Type)
+}
+
+// parseAPIError builds an APIError from a non-2xx response body, tagging
+// it with the response's status code and, for 429/503s, its Retry-After.
+func (api API) parseAPIError(resp *http.Response, data []byte) error {
+	api.logger().Errorf("MAILCHIMP ERROR %s", string(data))
+
+	apiError := new(APIError)
+	if err := json.Unmarshal(data, apiError); err != nil {
+		return err
+	}
+
+	apiError.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			apiError.RetryAfter = retryAfter
+		}
+	}
+
+	return apiError
+}
+
+// parseRetryAfter parses a Retry-After header in either of its two
+// allowed forms: delta-seconds ("120") or an HTTP-date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := time.ParseDuration(header + "s"); err == nil {
+		return seconds, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date), true
+	}
+
+	return 0, false
+}
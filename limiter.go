@@ -0,0 +1,61 @@
+package gochimp3
+
+import (
+	"context"
+	"sync"
+)
+
+// Limiter paces outgoing requests, e.g. for per-second pacing on top of
+// the concurrency cap. golang.org/x/time/rate.Limiter already satisfies
+// this via its Wait method.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// DefaultMaxConcurrent is the concurrent-connection cap Mailchimp
+// enforces per account, and the default for API.MaxConcurrent.
+const DefaultMaxConcurrent = 10
+
+// maxConcurrent resolves the effective concurrency cap for an API
+// instance: its MaxConcurrent field, or DefaultMaxConcurrent if unset.
+func maxConcurrent(n int) int {
+	if n <= 0 {
+		return DefaultMaxConcurrent
+	}
+	return n
+}
+
+// concurrencyLimiter caps in-flight requests. It's held behind a
+// pointer on API so every value-receiver copy of an *API shares the
+// same limiter, and resized lazily to track live changes to
+// API.MaxConcurrent (an API's sem is otherwise built once, at
+// construction time, which would make the field a no-op after that).
+type concurrencyLimiter struct {
+	mu   sync.Mutex
+	size int
+	ch   chan struct{}
+}
+
+func newConcurrencyLimiter(size int) *concurrencyLimiter {
+	return &concurrencyLimiter{size: size, ch: make(chan struct{}, size)}
+}
+
+// acquire blocks until a slot is free or ctx is done, resizing the
+// underlying channel first if size no longer matches the limiter's
+// current capacity. The returned release func frees the acquired slot.
+func (l *concurrencyLimiter) acquire(ctx context.Context, size int) (release func(), err error) {
+	l.mu.Lock()
+	if size != l.size {
+		l.size = size
+		l.ch = make(chan struct{}, size)
+	}
+	ch := l.ch
+	l.mu.Unlock()
+
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
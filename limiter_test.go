@@ -0,0 +1,104 @@
+package gochimp3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentHelper(t *testing.T) {
+	if got := maxConcurrent(0); got != DefaultMaxConcurrent {
+		t.Errorf("maxConcurrent(0) = %d, want %d", got, DefaultMaxConcurrent)
+	}
+	if got := maxConcurrent(-1); got != DefaultMaxConcurrent {
+		t.Errorf("maxConcurrent(-1) = %d, want %d", got, DefaultMaxConcurrent)
+	}
+	if got := maxConcurrent(3); got != 3 {
+		t.Errorf("maxConcurrent(3) = %d, want 3", got)
+	}
+}
+
+func TestNewSizesSemFromMaxConcurrent(t *testing.T) {
+	api := New("fake-us1")
+	if got := cap(api.sem.ch); got != api.MaxConcurrent {
+		t.Errorf("cap(sem) = %d, want MaxConcurrent = %d", got, api.MaxConcurrent)
+	}
+}
+
+func concurrencyTestServer(peak *int32) *httptest.Server {
+	var current int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+
+		for {
+			p := atomic.LoadInt32(peak)
+			if n <= p || atomic.CompareAndSwapInt32(peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func fireConcurrentRequests(api *API, n int) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = api.RequestOk(http.MethodGet, "/")
+		}()
+	}
+	wg.Wait()
+}
+
+// TestMaxConcurrentTakesEffectAfterNew reproduces the only way a caller
+// outside this package can configure concurrency: build via New, then
+// set the exported MaxConcurrent field before firing requests. It must
+// actually cap in-flight requests, not just the stale value captured at
+// New time.
+func TestMaxConcurrentTakesEffectAfterNew(t *testing.T) {
+	var peak int32
+	srv := concurrencyTestServer(&peak)
+	defer srv.Close()
+
+	api := New("fake-us1")
+	api.MaxConcurrent = 2
+	api.endpoint = srv.URL
+
+	fireConcurrentRequests(api, 6)
+
+	if peak > 2 {
+		t.Errorf("observed %d concurrent requests, want at most MaxConcurrent=2", peak)
+	}
+}
+
+// TestMaxConcurrentCanBeRaisedAfterNew exercises the same live-resize
+// path in the other direction, confirming the limiter isn't just
+// permanently shrunk once changed.
+func TestMaxConcurrentCanBeRaisedAfterNew(t *testing.T) {
+	var peak int32
+	srv := concurrencyTestServer(&peak)
+	defer srv.Close()
+
+	api := New("fake-us1")
+	api.MaxConcurrent = 1
+	api.endpoint = srv.URL
+	fireConcurrentRequests(api, 3)
+
+	api.MaxConcurrent = 5
+	fireConcurrentRequests(api, 5)
+
+	if peak < 2 {
+		t.Errorf("observed peak concurrency %d after raising MaxConcurrent to 5, want >1", peak)
+	}
+	if peak > 5 {
+		t.Errorf("observed %d concurrent requests, want at most MaxConcurrent=5", peak)
+	}
+}
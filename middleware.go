@@ -0,0 +1,51 @@
+package gochimp3
+
+import (
+	"log"
+	"net/http"
+)
+
+// RequestMiddleware runs just before a request is sent; returning an
+// error aborts that attempt before it reaches the network. The retry loop
+// in RequestWithContext treats such an error like a network error and
+// retries it under API.Backoff.
+type RequestMiddleware func(*http.Request) error
+
+// ResponseMiddleware runs right after a response is received, before its
+// body is read and unmarshalled into the caller's response value.
+type ResponseMiddleware func(*http.Response) error
+
+// Logger lets callers route gochimp3's debug/error tracing to their own
+// logging stack (zerolog, zap, etc.) instead of the standard "log"
+// package.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// OnBeforeRequest registers a middleware invoked, in order, just before
+// each HTTP request is sent.
+func (api *API) OnBeforeRequest(m RequestMiddleware) {
+	api.beforeRequest = append(api.beforeRequest, m)
+}
+
+// OnAfterResponse registers a middleware invoked, in order, right after
+// each HTTP response is received.
+func (api *API) OnAfterResponse(m ResponseMiddleware) {
+	api.afterResponse = append(api.afterResponse, m)
+}
+
+func (api API) logger() Logger {
+	if api.Logger != nil {
+		return api.Logger
+	}
+	return stdLogger{}
+}
+
+// stdLogger is the default Logger, preserving the package's historical
+// behavior of writing debug/error tracing through the standard "log"
+// package.
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...interface{}) { log.Printf(format, args...) }
+func (stdLogger) Errorf(format string, args ...interface{}) { log.Printf(format, args...) }
@@ -0,0 +1,85 @@
+package gochimp3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// authMode selects how rawRequest authenticates outgoing requests.
+type authMode int
+
+const (
+	authModeAPIKey authMode = iota
+	authModeOAuth
+)
+
+const oauthMetadataURL = "https://login.mailchimp.com/oauth2/metadata"
+
+// oauthMetadata is the response from Mailchimp's OAuth2 metadata
+// endpoint, used to discover which datacenter an access token belongs
+// to.
+type oauthMetadata struct {
+	DC          string `json:"dc"`
+	APIEndpoint string `json:"api_endpoint"`
+}
+
+// NewOAuth creates an API authenticated with an OAuth2 access token,
+// typically obtained via the oauth package's ExchangeCode, instead of an
+// API key. It calls Mailchimp's metadata endpoint to discover the
+// account's API endpoint, mirroring what New does from the key suffix.
+func NewOAuth(accessToken string) (*API, error) {
+	return NewOAuthWithContext(context.Background(), accessToken)
+}
+
+// NewOAuthWithContext behaves like NewOAuth but carries ctx through the
+// metadata lookup.
+func NewOAuthWithContext(ctx context.Context, accessToken string) (*API, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, oauthMetadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "OAuth "+accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gochimp3: oauth metadata request failed with status %d: %s", resp.StatusCode, string(data))
+	}
+
+	var meta oauthMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	if meta.APIEndpoint == "" {
+		return nil, fmt.Errorf("gochimp3: oauth metadata response did not include an api_endpoint")
+	}
+
+	u, err := url.Parse(meta.APIEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = Version
+
+	api := &API{
+		User:          "gochimp3",
+		Key:           accessToken,
+		authMode:      authModeOAuth,
+		endpoint:      u.String(),
+		MaxConcurrent: DefaultMaxConcurrent,
+	}
+	api.sem = newConcurrencyLimiter(maxConcurrent(api.MaxConcurrent))
+	return api, nil
+}